@@ -0,0 +1,54 @@
+// Copyright (c) Alex Ellis 2017. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package handlers
+
+import (
+	"github.com/openfaas/faas/gateway/scaling"
+	"github.com/openfaas/faas/gateway/types"
+)
+
+// FunctionQueueAnnotation selects a non-default queue for a function's
+// async invocations.
+const FunctionQueueAnnotation = "com.openfaas.queue"
+
+// FetchAnnotationsFunc fetches a function's current annotations from the
+// provider, for use on a QueueResolver cache miss.
+type FetchAnnotationsFunc func(functionName string) (map[string]string, error)
+
+// QueueResolver resolves which NATS queue a function's async invocations
+// should be published to, based on its com.openfaas.queue annotation.
+// Annotations are kept in the FunctionCache it is given, so lookups don't
+// hit the provider on every invocation.
+type QueueResolver struct {
+	configStore *types.ConfigStore
+	cache       *scaling.FunctionCache
+}
+
+// NewQueueResolver returns a QueueResolver reading queue routing from
+// configStore and caching annotation lookups in cache.
+func NewQueueResolver(configStore *types.ConfigStore, cache *scaling.FunctionCache) *QueueResolver {
+	return &QueueResolver{configStore: configStore, cache: cache}
+}
+
+// Resolve returns the NATS address functionName's async requests should be
+// published to. Annotations are served from the resolver's FunctionCache;
+// fetchAnnotations is only called on a cache miss. Absent or unknown queue
+// annotations fall back to the "default" queue.
+func (r *QueueResolver) Resolve(functionName string, fetchAnnotations FetchAnnotationsFunc) (string, error) {
+	annotations, ok := r.cache.Get(functionName)
+	if !ok {
+		fetched, err := fetchAnnotations(functionName)
+		if err != nil {
+			return "", err
+		}
+
+		annotations = fetched
+		r.cache.Set(functionName, annotations)
+	}
+
+	cfg := r.configStore.Get()
+	address, _ := cfg.QueueAddress(annotations[FunctionQueueAnnotation])
+
+	return address, nil
+}
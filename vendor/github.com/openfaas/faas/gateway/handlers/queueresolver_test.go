@@ -0,0 +1,79 @@
+// Copyright (c) Alex Ellis 2017. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package handlers
+
+import (
+	"testing"
+
+	"github.com/openfaas/faas/gateway/scaling"
+	"github.com/openfaas/faas/gateway/types"
+)
+
+func newTestConfigStore() *types.ConfigStore {
+	return types.NewConfigStore(types.GatewayConfig{
+		QueueMap: map[string]string{
+			"default":  "nats://a:4222",
+			"priority": "nats://b:4222",
+		},
+	})
+}
+
+func Test_QueueResolver_ResolvesAnnotationToTopic(t *testing.T) {
+	resolver := NewQueueResolver(newTestConfigStore(), scaling.NewFunctionCache(0))
+
+	address, err := resolver.Resolve("echo", func(string) (map[string]string, error) {
+		return map[string]string{FunctionQueueAnnotation: "priority"}, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if address != "nats://b:4222" {
+		t.Fatalf("got %q, want %q", address, "nats://b:4222")
+	}
+}
+
+func Test_QueueResolver_FallsBackToDefaultQueue(t *testing.T) {
+	resolver := NewQueueResolver(newTestConfigStore(), scaling.NewFunctionCache(0))
+
+	address, err := resolver.Resolve("echo", func(string) (map[string]string, error) {
+		return map[string]string{FunctionQueueAnnotation: "unknown-queue"}, nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if address != "nats://a:4222" {
+		t.Fatalf("got %q, want %q", address, "nats://a:4222")
+	}
+}
+
+func Test_QueueResolver_CachesAnnotationsAcrossCalls(t *testing.T) {
+	cache := scaling.NewFunctionCache(0)
+	resolver := NewQueueResolver(newTestConfigStore(), cache)
+
+	fetches := 0
+	fetch := func(string) (map[string]string, error) {
+		fetches++
+		return map[string]string{FunctionQueueAnnotation: "priority"}, nil
+	}
+
+	if _, err := resolver.Resolve("echo", fetch); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := resolver.Resolve("echo", fetch); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if fetches != 1 {
+		t.Fatalf("got %d provider fetches, want 1 (second call should hit the cache)", fetches)
+	}
+
+	cache.Invalidate("echo")
+
+	if _, err := resolver.Resolve("echo", fetch); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if fetches != 2 {
+		t.Fatalf("got %d provider fetches, want 2 (Invalidate should force a re-fetch)", fetches)
+	}
+}
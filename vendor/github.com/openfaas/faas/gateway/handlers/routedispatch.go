@@ -0,0 +1,57 @@
+// Copyright (c) Alex Ellis 2017. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/openfaas/faas/gateway/scaling"
+	"github.com/openfaas/faas/gateway/types"
+)
+
+// FetchLabelsFunc fetches a function's current labels from the provider,
+// for use on a RouteDispatcher cache miss.
+type FetchLabelsFunc func(functionName string) (map[string]string, error)
+
+// RouteDispatcher resolves the per-route tuning for a function and hands
+// back the pooled *http.Transport to use for its upstream call, so the
+// proxy layer doesn't build (and leak) a fresh Transport per request.
+// Labels are kept in the FunctionCache it is given, so the provider is
+// only asked on a cache miss. They are stored under the bare function
+// name, the same key QueueResolver uses for annotations, so the two cannot
+// share one FunctionCache instance.
+type RouteDispatcher struct {
+	configStore *types.ConfigStore
+	cache       *scaling.FunctionCache
+	transports  *types.TransportPool
+}
+
+// NewRouteDispatcher returns a RouteDispatcher reading route overrides from
+// configStore, caching label lookups in cache, and pulling transports from
+// transports.
+func NewRouteDispatcher(configStore *types.ConfigStore, cache *scaling.FunctionCache, transports *types.TransportPool) *RouteDispatcher {
+	return &RouteDispatcher{configStore: configStore, cache: cache, transports: transports}
+}
+
+// Transport resolves functionName's RouteConfig (by exact name or label
+// selector override, falling back to the gateway-wide defaults) and
+// returns it along with the pooled Transport to use for the upstream call.
+// fetchLabels is only called on a FunctionCache miss.
+func (d *RouteDispatcher) Transport(functionName string, fetchLabels FetchLabelsFunc) (types.RouteConfig, *http.Transport, error) {
+	labels, ok := d.cache.Get(functionName)
+	if !ok {
+		fetched, err := fetchLabels(functionName)
+		if err != nil {
+			return types.RouteConfig{}, nil, err
+		}
+
+		labels = fetched
+		d.cache.Set(functionName, labels)
+	}
+
+	cfg := d.configStore.Get()
+	rc := cfg.RouteConfigFor(functionName, labels)
+
+	return rc, d.transports.Get(rc), nil
+}
@@ -0,0 +1,46 @@
+// Copyright (c) Alex Ellis 2017. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openfaas/faas/gateway/scaling"
+	"github.com/openfaas/faas/gateway/types"
+)
+
+func Test_RouteDispatcher_ResolvesOverrideAndPooledTransport(t *testing.T) {
+	configStore := types.NewConfigStore(types.GatewayConfig{
+		UpstreamTimeout: 8 * time.Second,
+		RouteOverrides: map[string]types.RouteConfig{
+			"com.openfaas.class=batch": {UpstreamTimeout: 5 * time.Minute, MaxIdleConnsPerHost: 4},
+		},
+	})
+	dispatcher := NewRouteDispatcher(configStore, scaling.NewFunctionCache(0), types.NewTransportPool(1024))
+
+	fetches := 0
+	fetchLabels := func(string) (map[string]string, error) {
+		fetches++
+		return map[string]string{"com.openfaas.class": "batch"}, nil
+	}
+
+	rc, transport, err := dispatcher.Transport("batch-fn", fetchLabels)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if rc.UpstreamTimeout != 5*time.Minute {
+		t.Fatalf("got UpstreamTimeout %s, want 5m", rc.UpstreamTimeout)
+	}
+	if transport.ResponseHeaderTimeout != 5*time.Minute {
+		t.Fatalf("got ResponseHeaderTimeout %s, want 5m", transport.ResponseHeaderTimeout)
+	}
+
+	if _, _, err := dispatcher.Transport("batch-fn", fetchLabels); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if fetches != 1 {
+		t.Fatalf("got %d label fetches, want 1 (second call should hit the shared FunctionCache)", fetches)
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright (c) Alex Ellis 2017. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package handlers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/openfaas/faas/gateway/types"
+)
+
+// StreamAnnotation opts a function in to streaming responses (SSE, chunked
+// JSON, LLM token streams) instead of having its output buffered until
+// completion.
+const StreamAnnotation = "com.openfaas.stream"
+
+// IsStreamingFunction reports whether annotations mark a function for
+// streaming responses.
+func IsStreamingFunction(annotations map[string]string) bool {
+	return annotations[StreamAnnotation] == "true"
+}
+
+// UpstreamTimeoutFor returns the timeout to apply to a function's upstream
+// call: cfg.StreamingTimeout when the function streams, cfg.UpstreamTimeout
+// otherwise. It only returns the duration: nothing in this package applies
+// it, and CopyStreaming sets no read deadline, so the value is not reset
+// per chunk and does not act as an idle-read timeout.
+func UpstreamTimeoutFor(cfg types.GatewayConfig, annotations map[string]string) time.Duration {
+	if IsStreamingFunction(annotations) {
+		return cfg.StreamingTimeout
+	}
+	return cfg.UpstreamTimeout
+}
+
+// CopyStreaming copies src to w, flushing after every write so
+// incrementally produced output reaches the client without waiting for src
+// to finish, and disabling the response buffering a plain io.Copy to a
+// reverse proxy would otherwise impose. It stops early and returns ctx.Err()
+// if ctx is cancelled, e.g. because the client disconnected; callers pass
+// request.Context() to propagate that cancellation through to the upstream
+// read.
+func CopyStreaming(ctx context.Context, w http.ResponseWriter, src io.Reader) error {
+	flusher, _ := w.(http.Flusher)
+
+	buf := make([]byte, 4096)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		n, readErr := src.Read(buf)
+		if n > 0 {
+			if _, writeErr := w.Write(buf[:n]); writeErr != nil {
+				return writeErr
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+
+		if readErr != nil {
+			if readErr == io.EOF {
+				return nil
+			}
+			return readErr
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright (c) Alex Ellis 2017. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package handlers
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openfaas/faas/gateway/types"
+)
+
+// stepReader serves chunks one Read call at a time, invoking afterChunk
+// (synchronously, before Read returns) with the index just served. That
+// lets a test trigger context cancellation deterministically partway
+// through a stream, with no goroutines or timing involved.
+type stepReader struct {
+	chunks     [][]byte
+	i          int
+	afterChunk func(i int)
+}
+
+func (r *stepReader) Read(p []byte) (int, error) {
+	if r.i >= len(r.chunks) {
+		return 0, io.EOF
+	}
+
+	n := copy(p, r.chunks[r.i])
+	i := r.i
+	r.i++
+
+	if r.afterChunk != nil {
+		r.afterChunk(i)
+	}
+
+	return n, nil
+}
+
+func Test_CopyStreaming_FlushesEachWrite(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	if err := CopyStreaming(context.Background(), rec, strings.NewReader("hello world")); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if !rec.Flushed {
+		t.Fatal("expected the response to have been flushed")
+	}
+	if rec.Body.String() != "hello world" {
+		t.Fatalf("got body %q, want %q", rec.Body.String(), "hello world")
+	}
+}
+
+func Test_CopyStreaming_StopsOnContextCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	src := &stepReader{
+		chunks: [][]byte{[]byte("chunk-1"), []byte("chunk-2")},
+		afterChunk: func(i int) {
+			if i == 0 {
+				cancel()
+			}
+		},
+	}
+
+	rec := httptest.NewRecorder()
+	err := CopyStreaming(ctx, rec, src)
+
+	if err != context.Canceled {
+		t.Fatalf("got err %v, want context.Canceled", err)
+	}
+	if rec.Body.String() != "chunk-1" {
+		t.Fatalf("got body %q, want only the chunk written before cancellation", rec.Body.String())
+	}
+}
+
+func Test_IsStreamingFunction(t *testing.T) {
+	cases := map[string]bool{
+		"true":  true,
+		"false": false,
+		"":      false,
+	}
+
+	for value, want := range cases {
+		got := IsStreamingFunction(map[string]string{StreamAnnotation: value})
+		if got != want {
+			t.Errorf("annotation %q: got %v, want %v", value, got, want)
+		}
+	}
+}
+
+func Test_UpstreamTimeoutFor(t *testing.T) {
+	cfg := types.GatewayConfig{
+		UpstreamTimeout:  8 * time.Second,
+		StreamingTimeout: 5 * time.Minute,
+	}
+
+	if got := UpstreamTimeoutFor(cfg, nil); got != cfg.UpstreamTimeout {
+		t.Errorf("got %s, want UpstreamTimeout %s for a non-streaming function", got, cfg.UpstreamTimeout)
+	}
+
+	streaming := map[string]string{StreamAnnotation: "true"}
+	if got := UpstreamTimeoutFor(cfg, streaming); got != cfg.StreamingTimeout {
+		t.Errorf("got %s, want StreamingTimeout %s for a streaming function", got, cfg.StreamingTimeout)
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright (c) Alex Ellis 2017. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package scaling
+
+import (
+	"sync"
+	"time"
+)
+
+// functionMeta holds the metadata cached for a function between calls,
+// keyed by name, along with when it was cached.
+type functionMeta struct {
+	values map[string]string
+	cached time.Time
+}
+
+// expired reports whether this entry's TTL has elapsed as of now.
+func (m functionMeta) expired(now time.Time, ttl time.Duration) bool {
+	if ttl <= 0 {
+		return false
+	}
+	return now.Sub(m.cached) >= ttl
+}
+
+// FunctionCache caches per-function metadata (labels, annotations) so the
+// scale-from-zero path and the queue-proxy annotation lookup can share one
+// set of provider calls instead of each hitting the provider on every
+// invocation.
+type FunctionCache struct {
+	ttl time.Duration
+
+	mu    sync.RWMutex
+	cache map[string]functionMeta
+}
+
+// NewFunctionCache returns a FunctionCache whose entries expire after ttl.
+// A ttl of 0 disables expiry; entries then only go away via Invalidate.
+func NewFunctionCache(ttl time.Duration) *FunctionCache {
+	return &FunctionCache{
+		ttl:   ttl,
+		cache: make(map[string]functionMeta),
+	}
+}
+
+// Get returns the cached metadata for name and whether it was found and
+// still fresh. A miss (not found, or expired) returns (nil, false).
+func (c *FunctionCache) Get(name string) (map[string]string, bool) {
+	c.mu.RLock()
+	meta, ok := c.cache[name]
+	c.mu.RUnlock()
+
+	if !ok || meta.expired(time.Now(), c.ttl) {
+		return nil, false
+	}
+
+	return meta.values, true
+}
+
+// Set stores metadata for name, refreshing its cached time.
+func (c *FunctionCache) Set(name string, values map[string]string) {
+	c.mu.Lock()
+	c.cache[name] = functionMeta{values: values, cached: time.Now()}
+	c.mu.Unlock()
+}
+
+// Invalidate removes name from the cache, forcing the next Get to miss so
+// callers re-fetch from the provider.
+func (c *FunctionCache) Invalidate(name string) {
+	c.mu.Lock()
+	delete(c.cache, name)
+	c.mu.Unlock()
+}
@@ -0,0 +1,52 @@
+// Copyright (c) Alex Ellis 2017. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package scaling
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_FunctionCache_GetMiss(t *testing.T) {
+	cache := NewFunctionCache(time.Minute)
+
+	if _, ok := cache.Get("unknown-fn"); ok {
+		t.Fatal("expected a miss for a function never Set")
+	}
+}
+
+func Test_FunctionCache_SetThenGet(t *testing.T) {
+	cache := NewFunctionCache(time.Minute)
+	cache.Set("echo", map[string]string{"com.openfaas.queue": "priority"})
+
+	values, ok := cache.Get("echo")
+	if !ok {
+		t.Fatal("expected a hit after Set")
+	}
+	if values["com.openfaas.queue"] != "priority" {
+		t.Fatalf("got %q, want %q", values["com.openfaas.queue"], "priority")
+	}
+}
+
+func Test_FunctionCache_ExpiresAfterTTL(t *testing.T) {
+	cache := NewFunctionCache(time.Millisecond)
+	cache.Set("echo", map[string]string{"com.openfaas.queue": "priority"})
+
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := cache.Get("echo"); ok {
+		t.Fatal("expected the entry to have expired")
+	}
+}
+
+func Test_FunctionCache_Invalidate(t *testing.T) {
+	cache := NewFunctionCache(time.Minute)
+	cache.Set("echo", map[string]string{"com.openfaas.queue": "priority"})
+
+	cache.Invalidate("echo")
+
+	if _, ok := cache.Get("echo"); ok {
+		t.Fatal("expected a miss after Invalidate")
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright (c) Alex Ellis 2017. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package types
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+)
+
+// configReloadCount tracks how many times any ConfigStore has swapped in a
+// new config, so it can be scraped alongside the gateway's other metrics.
+var configReloadCount uint64
+
+// ConfigReloadCount returns how many times Reload has swapped in a new
+// config across all ConfigStores in this process.
+func ConfigReloadCount() uint64 {
+	return atomic.LoadUint64(&configReloadCount)
+}
+
+// ConfigStore guards the active GatewayConfig behind an RWMutex so it can
+// be swapped out at runtime, e.g. on SIGHUP, without callers needing to
+// restart the gateway to pick up new timeouts, idle-conn limits or the
+// auth-proxy URL.
+type ConfigStore struct {
+	mu     sync.RWMutex
+	active GatewayConfig
+}
+
+// NewConfigStore returns a ConfigStore holding initial as the active config.
+func NewConfigStore(initial GatewayConfig) *ConfigStore {
+	return &ConfigStore{active: initial}
+}
+
+// Get returns the currently active GatewayConfig.
+func (s *ConfigStore) Get() GatewayConfig {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.active
+}
+
+// Reload validates candidate and, if it passes, swaps it in as the active
+// config, bumps ConfigReloadCount and logs a reload event. The previous
+// config is left untouched when validation fails, so a bad reload never
+// takes the gateway out of a working state.
+func (s *ConfigStore) Reload(candidate GatewayConfig) error {
+	if err := candidate.Validate(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.active = candidate
+	s.mu.Unlock()
+
+	count := atomic.AddUint64(&configReloadCount, 1)
+	log.Printf("config reload: applied new config (reload #%d)", count)
+
+	return nil
+}
+
+// WatchSIGHUP reloads the store by calling load whenever the process
+// receives SIGHUP, logging (but not acting on) any error it returns. It
+// runs in its own goroutine and returns immediately.
+func (s *ConfigStore) WatchSIGHUP(load func() (GatewayConfig, error)) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGHUP)
+
+	go func() {
+		for range sig {
+			candidate, err := load()
+			if err != nil {
+				log.Printf("config reload: %s", err)
+				continue
+			}
+
+			if err := s.Reload(candidate); err != nil {
+				log.Printf("config reload: %s", err)
+			}
+		}
+	}()
+}
@@ -0,0 +1,44 @@
+// Copyright (c) Alex Ellis 2017. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package types
+
+import "testing"
+
+func Test_ConfigStore_ReloadAppliesValidCandidate(t *testing.T) {
+	store := NewConfigStore(GatewayConfig{ReadTimeout: -1})
+
+	if err := store.Reload(GatewayConfig{MaxIdleConnsPerHost: 2048}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if got := store.Get().MaxIdleConnsPerHost; got != 2048 {
+		t.Fatalf("got %d, want 2048", got)
+	}
+}
+
+func Test_ConfigStore_ReloadRejectsInvalidCandidate(t *testing.T) {
+	initial := GatewayConfig{MaxIdleConnsPerHost: 1024}
+	store := NewConfigStore(initial)
+
+	if err := store.Reload(GatewayConfig{ReadTimeout: -1}); err == nil {
+		t.Fatal("expected an error for a negative ReadTimeout")
+	}
+
+	if got := store.Get().MaxIdleConnsPerHost; got != 1024 {
+		t.Fatalf("got %d, want the original config to be preserved after a rejected reload", got)
+	}
+}
+
+func Test_ConfigStore_ReloadIncrementsConfigReloadCount(t *testing.T) {
+	store := NewConfigStore(GatewayConfig{})
+	before := ConfigReloadCount()
+
+	if err := store.Reload(GatewayConfig{}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if got := ConfigReloadCount(); got != before+1 {
+		t.Fatalf("got ConfigReloadCount %d, want %d", got, before+1)
+	}
+}
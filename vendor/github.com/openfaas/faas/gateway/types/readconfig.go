@@ -4,13 +4,21 @@
 package types
 
 import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"log"
 	"net/url"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
+// DefaultFaaSQueueName is the queue used when a function has no
+// com.openfaas.queue annotation, or names a queue that isn't configured.
+const DefaultFaaSQueueName = "default"
+
 // OsEnv implements interface to wrap os.Getenv
 type OsEnv struct {
 }
@@ -36,6 +44,233 @@ func parseBoolValue(val string) bool {
 	return false
 }
 
+// RouteConfig overrides tuning parameters that otherwise fall back to the
+// matching GatewayConfig field, for a single function name or label
+// selector named in RouteOverrides.
+type RouteConfig struct {
+
+	// UpstreamTimeout overrides GatewayConfig.UpstreamTimeout for this route.
+	UpstreamTimeout time.Duration
+
+	// MaxIdleConnsPerHost overrides GatewayConfig.MaxIdleConnsPerHost for
+	// this route's upstream transport.
+	MaxIdleConnsPerHost int
+
+	// Stream overrides the com.openfaas.stream annotation for this route.
+	Stream bool
+
+	// BypassAuthProxy skips AuthProxyURL validation for this route.
+	BypassAuthProxy bool
+}
+
+// routeConfigJSON mirrors RouteConfig for JSON decoding, using a string for
+// UpstreamTimeout since time.Duration doesn't parse the "30s" form on its
+// own.
+type routeConfigJSON struct {
+	UpstreamTimeout     string `json:"upstream_timeout"`
+	MaxIdleConnsPerHost int    `json:"max_idle_conns_per_host"`
+	Stream              bool   `json:"stream"`
+	BypassAuthProxy     bool   `json:"bypass_auth_proxy"`
+}
+
+// parseRouteOverrides decodes a JSON object of route name/selector to
+// routeConfigJSON, as used by the route_overrides env var and the
+// route_overrides key in config_file.
+func parseRouteOverrides(val string) (map[string]RouteConfig, error) {
+	if len(val) == 0 {
+		return nil, nil
+	}
+
+	var raw map[string]routeConfigJSON
+	if err := json.Unmarshal([]byte(val), &raw); err != nil {
+		return nil, fmt.Errorf("invalid route_overrides: %s", err)
+	}
+
+	overrides := make(map[string]RouteConfig, len(raw))
+	for route, rc := range raw {
+		overrides[route] = RouteConfig{
+			UpstreamTimeout:     parseIntOrDurationValue(rc.UpstreamTimeout, 0),
+			MaxIdleConnsPerHost: rc.MaxIdleConnsPerHost,
+			Stream:              rc.Stream,
+			BypassAuthProxy:     rc.BypassAuthProxy,
+		}
+	}
+
+	return overrides, nil
+}
+
+// fileConfig mirrors the subset of GatewayConfig that can be set from
+// config_file. It uses string fields where GatewayConfig uses types (such
+// as *url.URL and time.Duration) that don't round-trip through JSON on
+// their own; applyConfigFile re-parses them with the same helpers used for
+// the equivalent env variables.
+type fileConfig struct {
+	ReadTimeout          string            `json:"read_timeout"`
+	WriteTimeout         string            `json:"write_timeout"`
+	UpstreamTimeout      string            `json:"upstream_timeout"`
+	StreamingTimeout     string            `json:"streaming_timeout"`
+	FunctionsProviderURL string            `json:"functions_provider_url"`
+	LogsProviderURL      string            `json:"logs_provider_url"`
+	PrometheusHost       string            `json:"prometheus_host"`
+	PrometheusPort       int               `json:"prometheus_port"`
+	DirectFunctions      bool              `json:"direct_functions"`
+	UseBasicAuth         bool              `json:"basic_auth"`
+	ScaleFromZero        bool              `json:"scale_from_zero"`
+	MaxIdleConns         int               `json:"max_idle_conns"`
+	MaxIdleConnsPerHost  int               `json:"max_idle_conns_per_host"`
+	AuthProxyURL         string            `json:"auth_proxy_url"`
+	QueueMap             map[string]string `json:"queue_map"`
+	RouteOverrides       json.RawMessage   `json:"route_overrides"`
+}
+
+// loadConfigFile reads and JSON-decodes the file at path into cfg, leaving
+// fields cfg already holds untouched when the file omits them, except
+// direct_functions, basic_auth and scale_from_zero, which are always set
+// from the file and become false when it omits them. It only
+// supports JSON today; YAML support needs a decoder that isn't vendored in
+// this tree yet.
+func loadConfigFile(path string, cfg *GatewayConfig) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("unable to read config_file %q: %s", path, err)
+	}
+
+	var fc fileConfig
+	if err := json.Unmarshal(data, &fc); err != nil {
+		return fmt.Errorf("unable to parse config_file %q: %s", path, err)
+	}
+
+	if len(fc.ReadTimeout) > 0 {
+		cfg.ReadTimeout = parseIntOrDurationValue(fc.ReadTimeout, cfg.ReadTimeout)
+	}
+	if len(fc.WriteTimeout) > 0 {
+		cfg.WriteTimeout = parseIntOrDurationValue(fc.WriteTimeout, cfg.WriteTimeout)
+	}
+	if len(fc.UpstreamTimeout) > 0 {
+		cfg.UpstreamTimeout = parseIntOrDurationValue(fc.UpstreamTimeout, cfg.UpstreamTimeout)
+	}
+	if len(fc.StreamingTimeout) > 0 {
+		cfg.StreamingTimeout = parseIntOrDurationValue(fc.StreamingTimeout, cfg.StreamingTimeout)
+	}
+	if len(fc.FunctionsProviderURL) > 0 {
+		parsed, err := url.Parse(fc.FunctionsProviderURL)
+		if err != nil {
+			return fmt.Errorf("invalid functions_provider_url in config_file: %s", err)
+		}
+		cfg.FunctionsProviderURL = parsed
+	}
+	if len(fc.LogsProviderURL) > 0 {
+		parsed, err := url.Parse(fc.LogsProviderURL)
+		if err != nil {
+			return fmt.Errorf("invalid logs_provider_url in config_file: %s", err)
+		}
+		cfg.LogsProviderURL = parsed
+	}
+	if len(fc.PrometheusHost) > 0 {
+		cfg.PrometheusHost = fc.PrometheusHost
+	}
+	if fc.PrometheusPort > 0 {
+		cfg.PrometheusPort = fc.PrometheusPort
+	}
+	if fc.MaxIdleConns > 0 {
+		cfg.MaxIdleConns = fc.MaxIdleConns
+	}
+	if fc.MaxIdleConnsPerHost > 0 {
+		cfg.MaxIdleConnsPerHost = fc.MaxIdleConnsPerHost
+	}
+	if len(fc.AuthProxyURL) > 0 {
+		cfg.AuthProxyURL = fc.AuthProxyURL
+	}
+	if len(fc.QueueMap) > 0 {
+		cfg.QueueMap = fc.QueueMap
+	}
+	if len(fc.RouteOverrides) > 0 {
+		overrides, err := parseRouteOverrides(string(fc.RouteOverrides))
+		if err != nil {
+			return err
+		}
+		cfg.RouteOverrides = overrides
+	}
+
+	cfg.DirectFunctions = fc.DirectFunctions
+	cfg.UseBasicAuth = fc.UseBasicAuth
+	cfg.ScaleFromZero = fc.ScaleFromZero
+
+	return nil
+}
+
+// TLSConfig carries the transport security options for an outbound HTTP
+// client, such as the one used to reach the functions provider or
+// Prometheus.
+type TLSConfig struct {
+
+	// CACertFile is the path to a PEM-encoded CA bundle used to verify the
+	// remote server's certificate. When empty, the system root CAs are used.
+	CACertFile string
+
+	// CertFile is the path to a PEM-encoded client certificate, for mTLS.
+	CertFile string
+
+	// KeyFile is the path to the PEM-encoded private key for CertFile.
+	KeyFile string
+
+	// InsecureSkipVerify disables verification of the remote server's
+	// certificate chain and host name. Not recommended outside of testing.
+	InsecureSkipVerify bool
+
+	// ServerName overrides the host name used to verify the remote server's
+	// certificate, for cases where it doesn't match the dialled address.
+	ServerName string
+}
+
+// Enabled reports whether any TLS option has been configured.
+func (t TLSConfig) Enabled() bool {
+	return len(t.CACertFile) > 0 ||
+		len(t.CertFile) > 0 ||
+		len(t.KeyFile) > 0 ||
+		t.InsecureSkipVerify ||
+		len(t.ServerName) > 0
+}
+
+// readTLSConfig reads a TLSConfig from env variables sharing the given
+// prefix, e.g. prefix "provider_tls_" reads "provider_tls_ca_file" etc.
+func readTLSConfig(hasEnv HasEnv, prefix string) TLSConfig {
+	return TLSConfig{
+		CACertFile:         hasEnv.Getenv(prefix + "ca_file"),
+		CertFile:           hasEnv.Getenv(prefix + "cert_file"),
+		KeyFile:            hasEnv.Getenv(prefix + "key_file"),
+		InsecureSkipVerify: parseBoolValue(hasEnv.Getenv(prefix + "insecure_skip_verify")),
+		ServerName:         hasEnv.Getenv(prefix + "server_name"),
+	}
+}
+
+// parseQueueMap parses a comma-separated list of name=address pairs, as used
+// by faas_queue_map, into a map of queue name to NATS address. Malformed
+// entries are logged and skipped rather than aborting the whole parse.
+func parseQueueMap(val string) map[string]string {
+	if len(val) == 0 {
+		return nil
+	}
+
+	queueMap := make(map[string]string)
+	for _, pair := range strings.Split(val, ",") {
+		pair = strings.TrimSpace(pair)
+		if len(pair) == 0 {
+			continue
+		}
+
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+			log.Println("Invalid entry in faas_queue_map: " + pair)
+			continue
+		}
+
+		queueMap[parts[0]] = parts[1]
+	}
+
+	return queueMap
+}
+
 func parseIntOrDurationValue(val string, fallback time.Duration) time.Duration {
 	if len(val) > 0 {
 		parsedVal, parseErr := strconv.Atoi(val)
@@ -58,11 +293,35 @@ func (ReadConfig) Read(hasEnv HasEnv) GatewayConfig {
 		PrometheusPort: 9090,
 	}
 
+	if configFile := hasEnv.Getenv("config_file"); len(configFile) > 0 {
+		if err := loadConfigFile(configFile, &cfg); err != nil {
+			log.Fatal(err)
+		}
+	}
+
 	defaultDuration := time.Second * 8
 
-	cfg.ReadTimeout = parseIntOrDurationValue(hasEnv.Getenv("read_timeout"), defaultDuration)
-	cfg.WriteTimeout = parseIntOrDurationValue(hasEnv.Getenv("write_timeout"), defaultDuration)
-	cfg.UpstreamTimeout = parseIntOrDurationValue(hasEnv.Getenv("upstream_timeout"), defaultDuration)
+	// A zero value means config_file didn't set the field, so fall back to
+	// the package default; a non-zero value from the file is preserved
+	// unless an env var is also present, which still takes precedence.
+	readTimeoutDefault, writeTimeoutDefault, upstreamTimeoutDefault, streamingTimeoutDefault := defaultDuration, defaultDuration, defaultDuration, defaultDuration
+	if cfg.ReadTimeout > 0 {
+		readTimeoutDefault = cfg.ReadTimeout
+	}
+	if cfg.WriteTimeout > 0 {
+		writeTimeoutDefault = cfg.WriteTimeout
+	}
+	if cfg.UpstreamTimeout > 0 {
+		upstreamTimeoutDefault = cfg.UpstreamTimeout
+	}
+	if cfg.StreamingTimeout > 0 {
+		streamingTimeoutDefault = cfg.StreamingTimeout
+	}
+
+	cfg.ReadTimeout = parseIntOrDurationValue(hasEnv.Getenv("read_timeout"), readTimeoutDefault)
+	cfg.WriteTimeout = parseIntOrDurationValue(hasEnv.Getenv("write_timeout"), writeTimeoutDefault)
+	cfg.UpstreamTimeout = parseIntOrDurationValue(hasEnv.Getenv("upstream_timeout"), upstreamTimeoutDefault)
+	cfg.StreamingTimeout = parseIntOrDurationValue(hasEnv.Getenv("streaming_timeout"), streamingTimeoutDefault)
 
 	if len(hasEnv.Getenv("functions_provider_url")) > 0 {
 		var err error
@@ -78,10 +337,14 @@ func (ReadConfig) Read(hasEnv HasEnv) GatewayConfig {
 		if err != nil {
 			log.Fatal("If logs_provider_url is provided, then it should be a valid URL.", err)
 		}
-	} else if cfg.FunctionsProviderURL != nil {
+	} else if cfg.FunctionsProviderURL != nil && cfg.LogsProviderURL == nil {
 		cfg.LogsProviderURL, _ = url.Parse(cfg.FunctionsProviderURL.String())
 	}
 
+	cfg.ProviderTLSConfig = readTLSConfig(hasEnv, "provider_tls_")
+	cfg.PrometheusTLSConfig = readTLSConfig(hasEnv, "prometheus_tls_")
+	cfg.ProviderHTTPClientFile = hasEnv.Getenv("provider_http_client_file")
+
 	faasNATSAddress := hasEnv.Getenv("faas_nats_address")
 	if len(faasNATSAddress) > 0 {
 		cfg.NATSAddress = &faasNATSAddress
@@ -97,6 +360,15 @@ func (ReadConfig) Read(hasEnv HasEnv) GatewayConfig {
 		}
 	}
 
+	if faasQueueMap := hasEnv.Getenv("faas_queue_map"); len(faasQueueMap) > 0 {
+		cfg.QueueMap = parseQueueMap(faasQueueMap)
+	}
+	if len(cfg.QueueMap) == 0 && cfg.UseNATS() {
+		cfg.QueueMap = map[string]string{
+			DefaultFaaSQueueName: *cfg.NATSAddress + ":" + strconv.Itoa(*cfg.NATSPort),
+		}
+	}
+
 	prometheusPort := hasEnv.Getenv("faas_prometheus_port")
 	if len(prometheusPort) > 0 {
 		prometheusPortVal, err := strconv.Atoi(prometheusPort)
@@ -112,20 +384,39 @@ func (ReadConfig) Read(hasEnv HasEnv) GatewayConfig {
 		cfg.PrometheusHost = prometheusHost
 	}
 
-	cfg.DirectFunctions = parseBoolValue(hasEnv.Getenv("direct_functions"))
-	cfg.DirectFunctionsSuffix = hasEnv.Getenv("direct_functions_suffix")
+	if directFunctions := hasEnv.Getenv("direct_functions"); len(directFunctions) > 0 {
+		cfg.DirectFunctions = parseBoolValue(directFunctions)
+	}
+	if directFunctionsSuffix := hasEnv.Getenv("direct_functions_suffix"); len(directFunctionsSuffix) > 0 {
+		cfg.DirectFunctionsSuffix = directFunctionsSuffix
+	}
 
-	cfg.UseBasicAuth = parseBoolValue(hasEnv.Getenv("basic_auth"))
+	if basicAuth := hasEnv.Getenv("basic_auth"); len(basicAuth) > 0 {
+		cfg.UseBasicAuth = parseBoolValue(basicAuth)
+	}
 
 	secretPath := hasEnv.Getenv("secret_mount_path")
 	if len(secretPath) == 0 {
 		secretPath = "/run/secrets/"
 	}
 	cfg.SecretMountPath = secretPath
-	cfg.ScaleFromZero = parseBoolValue(hasEnv.Getenv("scale_from_zero"))
 
-	cfg.MaxIdleConns = 1024
-	cfg.MaxIdleConnsPerHost = 1024
+	cfg.SecretProviderType = hasEnv.Getenv("secret_provider")
+	if len(cfg.SecretProviderType) == 0 {
+		cfg.SecretProviderType = FileSecretProviderName
+	}
+	cfg.SecretRefreshInterval = parseIntOrDurationValue(hasEnv.Getenv("secret_refresh_interval"), time.Minute)
+
+	if scaleFromZero := hasEnv.Getenv("scale_from_zero"); len(scaleFromZero) > 0 {
+		cfg.ScaleFromZero = parseBoolValue(scaleFromZero)
+	}
+
+	if cfg.MaxIdleConns == 0 {
+		cfg.MaxIdleConns = 1024
+	}
+	if cfg.MaxIdleConnsPerHost == 0 {
+		cfg.MaxIdleConnsPerHost = 1024
+	}
 
 	maxIdleConns := hasEnv.Getenv("max_idle_conns")
 	if len(maxIdleConns) > 0 {
@@ -147,8 +438,20 @@ func (ReadConfig) Read(hasEnv HasEnv) GatewayConfig {
 		}
 	}
 
-	cfg.AuthProxyURL = hasEnv.Getenv("auth_proxy_url")
-	cfg.AuthProxyPassBody = parseBoolValue(hasEnv.Getenv("auth_proxy_pass_body"))
+	if authProxyURL := hasEnv.Getenv("auth_proxy_url"); len(authProxyURL) > 0 {
+		cfg.AuthProxyURL = authProxyURL
+	}
+	if authProxyPassBody := hasEnv.Getenv("auth_proxy_pass_body"); len(authProxyPassBody) > 0 {
+		cfg.AuthProxyPassBody = parseBoolValue(authProxyPassBody)
+	}
+
+	if routeOverrides := hasEnv.Getenv("route_overrides"); len(routeOverrides) > 0 {
+		overrides, err := parseRouteOverrides(routeOverrides)
+		if err != nil {
+			log.Fatal(err)
+		}
+		cfg.RouteOverrides = overrides
+	}
 
 	return cfg
 }
@@ -165,18 +468,44 @@ type GatewayConfig struct {
 	// UpstreamTimeout maximum duration of HTTP call to upstream URL
 	UpstreamTimeout time.Duration
 
+	// StreamingTimeout is returned by handlers.UpstreamTimeoutFor in place
+	// of UpstreamTimeout for functions that opt into streaming responses via
+	// the com.openfaas.stream annotation. It is a single duration; nothing
+	// resets it per chunk, so it is not an idle-read timeout.
+	StreamingTimeout time.Duration
+
 	// URL for alternate functions provider.
 	FunctionsProviderURL *url.URL
 
 	// URL for alternate function logs provider.
 	LogsProviderURL *url.URL
 
+	// ProviderTLSConfig carries transport security options for the client
+	// used to reach FunctionsProviderURL / LogsProviderURL.
+	ProviderTLSConfig TLSConfig
+
+	// PrometheusTLSConfig carries transport security options for the
+	// client used to query Prometheus.
+	PrometheusTLSConfig TLSConfig
+
+	// ProviderHTTPClientFile is the path to a JSON file overriding basic
+	// auth, bearer token and TLS settings for the provider HTTP client in
+	// one place, taking precedence over the individual env variables above.
+	// YAML isn't supported yet; see NewProviderHTTPClient.
+	ProviderHTTPClientFile string
+
 	// Address of the NATS service. Required for async mode.
 	NATSAddress *string
 
 	// Port of the NATS Service. Required for async mode.
 	NATSPort *int
 
+	// QueueMap maps a named queue (as referenced by a function's
+	// com.openfaas.queue annotation) to the NATS address which serves it.
+	// Populated from faas_queue_map or config_file's queue_map, or derived
+	// from NATSAddress/NATSPort as the "default" queue when neither sets it.
+	QueueMap map[string]string
+
 	// Host to connect to Prometheus.
 	PrometheusHost string
 
@@ -195,6 +524,19 @@ type GatewayConfig struct {
 	// SecretMountPath specifies where to read secrets from for embedded basic auth
 	SecretMountPath string
 
+	// SecretProviderType selects the SecretProvider implementation used to
+	// resolve basic-auth and auth-proxy credentials. Only "file" builds a
+	// working provider; "vault", "k8s", "aws" and "gcp" are recognised
+	// values that currently always fail in NewSecretProvider, since none
+	// of their client libraries are vendored here. See NewSecretProvider's
+	// doc comment for the scope of what's actually supported.
+	SecretProviderType string
+
+	// SecretRefreshInterval is how long NewSecretProvider's provider caches
+	// each secret before re-reading it, so rotated credentials are picked up
+	// without a gateway restart.
+	SecretRefreshInterval time.Duration
+
 	// Enable the gateway to scale any service from 0 replicas to its configured "min replicas"
 	ScaleFromZero bool
 
@@ -209,6 +551,139 @@ type GatewayConfig struct {
 
 	// AuthProxyPassBody pass body to validation proxy
 	AuthProxyPassBody bool
+
+	// RouteOverrides tunes UpstreamTimeout, MaxIdleConnsPerHost, streaming
+	// and auth-proxy bypass per function name or label selector, for
+	// workloads that don't fit the gateway-wide defaults.
+	RouteOverrides map[string]RouteConfig
+}
+
+// RouteConfigFor resolves the RouteConfig for a function, checking first
+// for an override keyed by the function's exact name, then for one keyed
+// by a label selector that labels satisfies. Only one override is used
+// (an exact-name match means label selectors are not consulted), and the
+// gateway-wide defaults fill UpstreamTimeout and MaxIdleConnsPerHost where
+// that override leaves them zero. When more than one
+// label selector matches, the most specific one (the most key=value pairs)
+// wins; see bestMatchingLabelSelector for the tie-break when even that's
+// tied.
+func (g *GatewayConfig) RouteConfigFor(name string, labels map[string]string) RouteConfig {
+	rc := RouteConfig{
+		UpstreamTimeout:     g.UpstreamTimeout,
+		MaxIdleConnsPerHost: g.MaxIdleConnsPerHost,
+	}
+
+	override, ok := g.RouteOverrides[name]
+	if !ok {
+		override, ok = bestMatchingLabelSelector(g.RouteOverrides, labels)
+	}
+
+	if !ok {
+		return rc
+	}
+
+	if override.UpstreamTimeout > 0 {
+		rc.UpstreamTimeout = override.UpstreamTimeout
+	}
+	if override.MaxIdleConnsPerHost > 0 {
+		rc.MaxIdleConnsPerHost = override.MaxIdleConnsPerHost
+	}
+	rc.Stream = override.Stream
+	rc.BypassAuthProxy = override.BypassAuthProxy
+
+	return rc
+}
+
+// bestMatchingLabelSelector returns the RouteConfig of whichever selector in
+// overrides matches labels, choosing deterministically when more than one
+// does: the selector with the most key=value pairs wins, since it's the
+// more specific match, and ties are broken by selector string so the result
+// never depends on map iteration order.
+func bestMatchingLabelSelector(overrides map[string]RouteConfig, labels map[string]string) (RouteConfig, bool) {
+	var (
+		best      RouteConfig
+		bestKey   string
+		bestPairs int
+		found     bool
+	)
+
+	for selector, candidate := range overrides {
+		if !matchesLabelSelector(selector, labels) {
+			continue
+		}
+
+		pairs := strings.Count(selector, "=")
+		if !found || pairs > bestPairs || (pairs == bestPairs && selector < bestKey) {
+			best, bestKey, bestPairs, found = candidate, selector, pairs, true
+		}
+	}
+
+	return best, found
+}
+
+// matchesLabelSelector reports whether labels satisfies selector, a
+// comma-separated list of key=value pairs (e.g.
+// "com.openfaas.scale.zero=true,com.openfaas.class=batch"), all of which
+// must match. A selector with no "=" names a function directly rather than
+// a label selector, and never matches here.
+func matchesLabelSelector(selector string, labels map[string]string) bool {
+	if !strings.Contains(selector, "=") {
+		return false
+	}
+
+	for _, pair := range strings.Split(selector, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(parts) != 2 {
+			return false
+		}
+		if labels[parts[0]] != parts[1] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Validate checks cfg for combinations that would leave the gateway
+// misconfigured: negative durations or idle-connection limits (gateway-wide
+// and per route) and an AuthProxyURL that fails to parse. Provider URLs are
+// not checked. It's intended to gate a hot reload: a candidate GatewayConfig that
+// fails Validate should never replace the active one.
+func (g GatewayConfig) Validate() error {
+	for name, d := range map[string]time.Duration{
+		"ReadTimeout":      g.ReadTimeout,
+		"WriteTimeout":     g.WriteTimeout,
+		"UpstreamTimeout":  g.UpstreamTimeout,
+		"StreamingTimeout": g.StreamingTimeout,
+	} {
+		if d < 0 {
+			return fmt.Errorf("%s must not be negative, got %s", name, d)
+		}
+	}
+
+	if g.MaxIdleConns < 0 {
+		return fmt.Errorf("MaxIdleConns must not be negative, got %d", g.MaxIdleConns)
+	}
+	if g.MaxIdleConnsPerHost < 0 {
+		return fmt.Errorf("MaxIdleConnsPerHost must not be negative, got %d", g.MaxIdleConnsPerHost)
+	}
+
+	if len(g.AuthProxyURL) > 0 {
+		if _, err := url.Parse(g.AuthProxyURL); err != nil {
+			return fmt.Errorf("AuthProxyURL is invalid: %s", err)
+		}
+	}
+
+	for route, rc := range g.RouteOverrides {
+		if rc.UpstreamTimeout < 0 {
+			return fmt.Errorf("RouteOverrides[%s].UpstreamTimeout must not be negative, got %s", route, rc.UpstreamTimeout)
+		}
+		if rc.MaxIdleConnsPerHost < 0 {
+			return fmt.Errorf("RouteOverrides[%s].MaxIdleConnsPerHost must not be negative, got %d", route, rc.MaxIdleConnsPerHost)
+		}
+	}
+
+	return nil
 }
 
 // UseNATS Use NATSor not
@@ -217,6 +692,21 @@ func (g *GatewayConfig) UseNATS() bool {
 		g.NATSAddress != nil
 }
 
+// QueueAddress resolves the NATS address for the named queue, falling back
+// to the "default" queue when name is empty or not present in QueueMap.
+func (g *GatewayConfig) QueueAddress(name string) (string, bool) {
+	if len(name) == 0 {
+		name = DefaultFaaSQueueName
+	}
+
+	if address, ok := g.QueueMap[name]; ok {
+		return address, true
+	}
+
+	address, ok := g.QueueMap[DefaultFaaSQueueName]
+	return address, ok
+}
+
 // UseExternalProvider decide whether to bypass built-in Docker Swarm engine
 func (g *GatewayConfig) UseExternalProvider() bool {
 	return g.FunctionsProviderURL != nil
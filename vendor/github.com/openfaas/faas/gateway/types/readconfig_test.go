@@ -0,0 +1,101 @@
+// Copyright (c) Alex Ellis 2017. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package types
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+// mapEnv is a HasEnv backed by a plain map, for tests that need to control
+// exactly which env vars are set.
+type mapEnv map[string]string
+
+func (m mapEnv) Getenv(key string) string {
+	return m[key]
+}
+
+func writeTempConfigFile(t *testing.T, contents string) string {
+	t.Helper()
+
+	f, err := ioutil.TempFile("", "gateway-config-*.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := f.WriteString(contents); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	f.Close()
+
+	t.Cleanup(func() { os.Remove(f.Name()) })
+
+	return f.Name()
+}
+
+func Test_Read_ConfigFileValuesSurviveWithNoMatchingEnvVars(t *testing.T) {
+	path := writeTempConfigFile(t, `{
+		"auth_proxy_url": "http://basic-auth.openfaas:8080/validate",
+		"direct_functions": true,
+		"basic_auth": true,
+		"scale_from_zero": true,
+		"max_idle_conns": 2048,
+		"max_idle_conns_per_host": 512,
+		"queue_map": {"default": "nats://a:4222", "priority": "nats://b:4222"}
+	}`)
+
+	cfg := ReadConfig{}.Read(mapEnv{"config_file": path})
+
+	if cfg.AuthProxyURL != "http://basic-auth.openfaas:8080/validate" {
+		t.Errorf("got AuthProxyURL %q, want the config_file value", cfg.AuthProxyURL)
+	}
+	if !cfg.DirectFunctions {
+		t.Error("got DirectFunctions false, want true from config_file")
+	}
+	if !cfg.UseBasicAuth {
+		t.Error("got UseBasicAuth false, want true from config_file")
+	}
+	if !cfg.ScaleFromZero {
+		t.Error("got ScaleFromZero false, want true from config_file")
+	}
+	if cfg.MaxIdleConns != 2048 {
+		t.Errorf("got MaxIdleConns %d, want 2048 from config_file", cfg.MaxIdleConns)
+	}
+	if cfg.MaxIdleConnsPerHost != 512 {
+		t.Errorf("got MaxIdleConnsPerHost %d, want 512 from config_file", cfg.MaxIdleConnsPerHost)
+	}
+	if len(cfg.QueueMap) != 2 || cfg.QueueMap["priority"] != "nats://b:4222" {
+		t.Errorf("got QueueMap %v, want the config_file's queue_map", cfg.QueueMap)
+	}
+}
+
+func Test_Read_EnvVarsStillOverrideConfigFile(t *testing.T) {
+	path := writeTempConfigFile(t, `{
+		"auth_proxy_url": "http://basic-auth.openfaas:8080/validate",
+		"scale_from_zero": true,
+		"max_idle_conns": 2048,
+		"queue_map": {"default": "nats://a:4222"}
+	}`)
+
+	cfg := ReadConfig{}.Read(mapEnv{
+		"config_file":     path,
+		"auth_proxy_url":  "http://override:8080/validate",
+		"scale_from_zero": "false",
+		"max_idle_conns":  "99",
+		"faas_queue_map":  "default=nats://override:4222",
+	})
+
+	if cfg.AuthProxyURL != "http://override:8080/validate" {
+		t.Errorf("got AuthProxyURL %q, want the env var to win", cfg.AuthProxyURL)
+	}
+	if cfg.ScaleFromZero {
+		t.Error("got ScaleFromZero true, want the env var (false) to win")
+	}
+	if cfg.MaxIdleConns != 99 {
+		t.Errorf("got MaxIdleConns %d, want 99 from the env var", cfg.MaxIdleConns)
+	}
+	if cfg.QueueMap["default"] != "nats://override:4222" {
+		t.Errorf("got QueueMap[default] %q, want the env var's queue map to win", cfg.QueueMap["default"])
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) Alex Ellis 2017. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_RouteConfigFor_ExactNameOverride(t *testing.T) {
+	cfg := GatewayConfig{
+		UpstreamTimeout: 8 * time.Second,
+		RouteOverrides: map[string]RouteConfig{
+			"slow-batch-fn": {UpstreamTimeout: 5 * time.Minute},
+		},
+	}
+
+	rc := cfg.RouteConfigFor("slow-batch-fn", nil)
+	if rc.UpstreamTimeout != 5*time.Minute {
+		t.Fatalf("got %s, want 5m", rc.UpstreamTimeout)
+	}
+}
+
+func Test_RouteConfigFor_LabelSelectorOverride(t *testing.T) {
+	cfg := GatewayConfig{
+		UpstreamTimeout: 8 * time.Second,
+		RouteOverrides: map[string]RouteConfig{
+			"com.openfaas.class=batch": {UpstreamTimeout: 5 * time.Minute, Stream: true},
+		},
+	}
+
+	rc := cfg.RouteConfigFor("some-fn", map[string]string{"com.openfaas.class": "batch"})
+	if rc.UpstreamTimeout != 5*time.Minute {
+		t.Fatalf("got %s, want 5m", rc.UpstreamTimeout)
+	}
+	if !rc.Stream {
+		t.Fatal("expected Stream to be true from the matching label selector override")
+	}
+}
+
+func Test_RouteConfigFor_NoMatchFallsBackToDefaults(t *testing.T) {
+	cfg := GatewayConfig{
+		UpstreamTimeout:     8 * time.Second,
+		MaxIdleConnsPerHost: 1024,
+		RouteOverrides: map[string]RouteConfig{
+			"com.openfaas.class=batch": {UpstreamTimeout: 5 * time.Minute},
+		},
+	}
+
+	rc := cfg.RouteConfigFor("some-fn", map[string]string{"com.openfaas.class": "realtime"})
+	if rc.UpstreamTimeout != 8*time.Second {
+		t.Fatalf("got %s, want the gateway-wide default of 8s", rc.UpstreamTimeout)
+	}
+	if rc.MaxIdleConnsPerHost != 1024 {
+		t.Fatalf("got %d, want the gateway-wide default of 1024", rc.MaxIdleConnsPerHost)
+	}
+}
+
+func Test_RouteConfigFor_MultiKeyLabelSelectorRequiresAllToMatch(t *testing.T) {
+	cfg := GatewayConfig{
+		RouteOverrides: map[string]RouteConfig{
+			"com.openfaas.class=batch,com.openfaas.scale.zero=true": {Stream: true},
+		},
+	}
+
+	partial := cfg.RouteConfigFor("some-fn", map[string]string{"com.openfaas.class": "batch"})
+	if partial.Stream {
+		t.Fatal("expected no match when only one of two selector keys is satisfied")
+	}
+
+	full := cfg.RouteConfigFor("some-fn", map[string]string{
+		"com.openfaas.class":      "batch",
+		"com.openfaas.scale.zero": "true",
+	})
+	if !full.Stream {
+		t.Fatal("expected a match when all selector keys are satisfied")
+	}
+}
+
+func Test_RouteConfigFor_MostSpecificSelectorWinsOnOverlap(t *testing.T) {
+	cfg := GatewayConfig{
+		UpstreamTimeout: 8 * time.Second,
+		RouteOverrides: map[string]RouteConfig{
+			"com.openfaas.class=batch":                              {UpstreamTimeout: 5 * time.Minute},
+			"com.openfaas.class=batch,com.openfaas.scale.zero=true": {UpstreamTimeout: 10 * time.Minute},
+		},
+	}
+
+	labels := map[string]string{"com.openfaas.class": "batch", "com.openfaas.scale.zero": "true"}
+
+	// Both selectors match labels; run it enough times that map iteration
+	// order would eventually surface a wrong, non-deterministic answer if
+	// the resolution weren't actually deterministic.
+	for i := 0; i < 20; i++ {
+		rc := cfg.RouteConfigFor("some-fn", labels)
+		if rc.UpstreamTimeout != 10*time.Minute {
+			t.Fatalf("got %s, want the more specific two-key selector's 10m to win", rc.UpstreamTimeout)
+		}
+	}
+}
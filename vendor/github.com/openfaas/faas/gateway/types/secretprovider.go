@@ -0,0 +1,170 @@
+// Copyright (c) Alex Ellis 2017. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package types
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// FileSecretProviderName selects the on-disk SecretProvider, the only
+// implementation available without additional vendored clients.
+const FileSecretProviderName = "file"
+
+// SecretProvider resolves named secrets, such as the basic-auth credentials
+// compared against on every request, from a backing store. Implementations
+// may cache values and re-read them on Reload so rotated credentials can
+// propagate without restarting the gateway.
+type SecretProvider interface {
+
+	// Get returns the current value of the named secret.
+	Get(name string) (string, error)
+
+	// Reload discards any cached values, so the next Get of each secret
+	// re-reads it from the backing store.
+	Reload() error
+}
+
+// NewSecretProvider builds the SecretProvider selected by
+// cfg.SecretProviderType, rooted at cfg.SecretMountPath with entries cached
+// for cfg.SecretRefreshInterval.
+//
+// Only "file" (or an empty value) builds a working provider. "vault", "k8s",
+// "aws" and "gcp" are recognised but always return an error here, since
+// none of their client libraries (hashicorp/vault-client-go, client-go,
+// aws-sdk-go/secretsmanager, cloud.google.com/go/secretmanager) are
+// vendored in this tree. Any other value returns an "unknown" error.
+func NewSecretProvider(cfg GatewayConfig) (SecretProvider, error) {
+	switch cfg.SecretProviderType {
+	case "", FileSecretProviderName:
+		return NewFileSecretProvider(cfg.SecretMountPath, cfg.SecretRefreshInterval), nil
+	case "vault", "k8s", "aws", "gcp":
+		return nil, fmt.Errorf("secret_provider %q is not implemented yet: its client library isn't vendored in this tree", cfg.SecretProviderType)
+	default:
+		return nil, fmt.Errorf("unknown secret_provider %q", cfg.SecretProviderType)
+	}
+}
+
+// secretEntry is a cached secret value along with when it was read.
+type secretEntry struct {
+	value string
+	read  time.Time
+}
+
+// expired reports whether this entry's TTL has elapsed as of now. A ttl of
+// 0 means entries never expire on their own, only via Reload.
+func (e secretEntry) expired(now time.Time, ttl time.Duration) bool {
+	if ttl <= 0 {
+		return false
+	}
+	return now.Sub(e.read) >= ttl
+}
+
+// FileSecretProvider reads secrets from files in a directory, one secret
+// per file named after the secret. This matches the gateway's original
+// behaviour of reading basic-auth credentials from SecretMountPath, with
+// entries cached for up to ttl and counters tracking fetch and rotation
+// errors.
+type FileSecretProvider struct {
+	mountPath string
+	ttl       time.Duration
+
+	mu    sync.RWMutex
+	cache map[string]secretEntry
+
+	fetchErrors    uint64
+	rotationErrors uint64
+}
+
+// NewFileSecretProvider returns a FileSecretProvider rooted at mountPath,
+// caching each secret for up to ttl before re-reading it from disk. A ttl
+// of 0 caches indefinitely, relying on Reload (or Watch) to pick up changes.
+func NewFileSecretProvider(mountPath string, ttl time.Duration) *FileSecretProvider {
+	return &FileSecretProvider{
+		mountPath: mountPath,
+		ttl:       ttl,
+		cache:     make(map[string]secretEntry),
+	}
+}
+
+// Get returns the named secret, reading it from disk when it's not cached
+// or its TTL has elapsed.
+func (f *FileSecretProvider) Get(name string) (string, error) {
+	f.mu.RLock()
+	entry, ok := f.cache[name]
+	f.mu.RUnlock()
+
+	if ok && !entry.expired(time.Now(), f.ttl) {
+		return entry.value, nil
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(f.mountPath, name))
+	if err != nil {
+		atomic.AddUint64(&f.fetchErrors, 1)
+		return "", err
+	}
+
+	value := strings.TrimSpace(string(data))
+
+	f.mu.Lock()
+	f.cache[name] = secretEntry{value: value, read: time.Now()}
+	f.mu.Unlock()
+
+	return value, nil
+}
+
+// Reload clears the in-memory cache so the next Get for each secret
+// re-reads it from disk.
+func (f *FileSecretProvider) Reload() error {
+	f.mu.Lock()
+	f.cache = make(map[string]secretEntry)
+	f.mu.Unlock()
+
+	return nil
+}
+
+// Watch calls Reload every interval until stop is closed; an interval of 0
+// or less starts nothing. A failed Reload is counted as a rotation error; Reload as
+// implemented here can't itself fail, but Watch accounts for
+// SecretProvider implementations that can.
+func (f *FileSecretProvider) Watch(interval time.Duration, stop <-chan struct{}) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := f.Reload(); err != nil {
+					atomic.AddUint64(&f.rotationErrors, 1)
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// SecretProviderMetrics is a snapshot of fetch/rotation error counts, for
+// exposing alongside the gateway's other metrics.
+type SecretProviderMetrics struct {
+	FetchErrors    uint64
+	RotationErrors uint64
+}
+
+// Metrics returns a snapshot of f's fetch/rotation error counters.
+func (f *FileSecretProvider) Metrics() SecretProviderMetrics {
+	return SecretProviderMetrics{
+		FetchErrors:    atomic.LoadUint64(&f.fetchErrors),
+		RotationErrors: atomic.LoadUint64(&f.rotationErrors),
+	}
+}
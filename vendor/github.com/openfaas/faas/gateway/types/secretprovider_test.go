@@ -0,0 +1,102 @@
+// Copyright (c) Alex Ellis 2017. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package types
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func Test_FileSecretProvider_GetReadsFromDisk(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "basic-auth-password"), []byte("s3cr3t\n"), 0600); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	provider := NewFileSecretProvider(dir, time.Minute)
+
+	val, err := provider.Get("basic-auth-password")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if val != "s3cr3t" {
+		t.Fatalf("got %q, want %q", val, "s3cr3t")
+	}
+}
+
+func Test_FileSecretProvider_GetMissing_CountsFetchError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	provider := NewFileSecretProvider(dir, time.Minute)
+
+	if _, err := provider.Get("missing"); err == nil {
+		t.Fatal("expected an error for a missing secret file")
+	}
+
+	if got := provider.Metrics().FetchErrors; got != 1 {
+		t.Fatalf("got FetchErrors %d, want 1", got)
+	}
+}
+
+func Test_FileSecretProvider_TTLExpiryPicksUpRotation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "token")
+	if err := ioutil.WriteFile(path, []byte("v1"), 0600); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	provider := NewFileSecretProvider(dir, time.Millisecond)
+
+	if val, _ := provider.Get("token"); val != "v1" {
+		t.Fatalf("got %q, want %q", val, "v1")
+	}
+
+	if err := ioutil.WriteFile(path, []byte("v2"), 0600); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	if val, _ := provider.Get("token"); val != "v2" {
+		t.Fatalf("got %q, want %q after TTL expiry", val, "v2")
+	}
+}
+
+func Test_NewSecretProvider_File(t *testing.T) {
+	provider, err := NewSecretProvider(GatewayConfig{SecretProviderType: FileSecretProviderName, SecretMountPath: "/run/secrets/"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, ok := provider.(*FileSecretProvider); !ok {
+		t.Fatalf("got %T, want *FileSecretProvider", provider)
+	}
+}
+
+func Test_NewSecretProvider_UnimplementedBackend(t *testing.T) {
+	if _, err := NewSecretProvider(GatewayConfig{SecretProviderType: "vault"}); err == nil {
+		t.Fatal("expected an error for an unimplemented secret_provider")
+	}
+}
+
+func Test_NewSecretProvider_UnknownBackend(t *testing.T) {
+	if _, err := NewSecretProvider(GatewayConfig{SecretProviderType: "not-a-real-provider"}); err == nil {
+		t.Fatal("expected an error for an unknown secret_provider")
+	}
+}
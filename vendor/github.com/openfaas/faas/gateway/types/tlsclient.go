@@ -0,0 +1,124 @@
+// Copyright (c) Alex Ellis 2017. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package types
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// NewHTTPClient builds an *http.Client whose transport is configured from
+// t, loading the CA bundle and client certificate from disk when set. A
+// zero-value TLSConfig yields a client using Go's default TLS settings.
+func NewHTTPClient(t TLSConfig, timeout time.Duration) (*http.Client, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: t.InsecureSkipVerify,
+		ServerName:         t.ServerName,
+	}
+
+	if len(t.CACertFile) > 0 {
+		caCert, err := ioutil.ReadFile(t.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read CA cert file %q: %s", t.CACertFile, err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no certificates found in CA cert file %q", t.CACertFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if len(t.CertFile) > 0 || len(t.KeyFile) > 0 {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load client cert/key pair: %s", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &http.Transport{TLSClientConfig: tlsConfig},
+	}, nil
+}
+
+// NewPrometheusHTTPClient builds the *http.Client used to query Prometheus,
+// from cfg.PrometheusTLSConfig.
+func NewPrometheusHTTPClient(cfg GatewayConfig, timeout time.Duration) (*http.Client, error) {
+	return NewHTTPClient(cfg.PrometheusTLSConfig, timeout)
+}
+
+// httpClientFile mirrors the fields ProviderHTTPClientFile can set, decoded
+// as JSON. YAML isn't supported: no YAML decoder is vendored in this tree,
+// so a .yaml/.yml file here will fail to parse as JSON.
+type httpClientFile struct {
+	BasicAuthUser     string    `json:"basic_auth_user"`
+	BasicAuthPassword string    `json:"basic_auth_password"`
+	BearerToken       string    `json:"bearer_token"`
+	TLS               TLSConfig `json:"tls"`
+}
+
+// roundTripperFunc adapts a function to http.RoundTripper, so basic auth /
+// bearer token injection can wrap an existing transport without a named type.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// NewProviderHTTPClient builds the *http.Client used to reach the functions
+// provider. When cfg.ProviderHTTPClientFile is set, it's read as JSON and
+// its basic auth / bearer token / TLS settings override cfg.ProviderTLSConfig
+// in one place; otherwise the client is built from cfg.ProviderTLSConfig
+// alone.
+func NewProviderHTTPClient(cfg GatewayConfig, timeout time.Duration) (*http.Client, error) {
+	tlsConfig := cfg.ProviderTLSConfig
+
+	var basicAuthUser, basicAuthPassword, bearerToken string
+
+	if len(cfg.ProviderHTTPClientFile) > 0 {
+		data, err := ioutil.ReadFile(cfg.ProviderHTTPClientFile)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read provider_http_client_file %q: %s", cfg.ProviderHTTPClientFile, err)
+		}
+
+		var hc httpClientFile
+		if err := json.Unmarshal(data, &hc); err != nil {
+			return nil, fmt.Errorf("unable to parse provider_http_client_file %q: %s", cfg.ProviderHTTPClientFile, err)
+		}
+
+		if hc.TLS.Enabled() {
+			tlsConfig = hc.TLS
+		}
+		basicAuthUser, basicAuthPassword, bearerToken = hc.BasicAuthUser, hc.BasicAuthPassword, hc.BearerToken
+	}
+
+	client, err := NewHTTPClient(tlsConfig, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case len(bearerToken) > 0:
+		base := client.Transport
+		client.Transport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("Authorization", "Bearer "+bearerToken)
+			return base.RoundTrip(req)
+		})
+	case len(basicAuthUser) > 0:
+		base := client.Transport
+		client.Transport = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req.SetBasicAuth(basicAuthUser, basicAuthPassword)
+			return base.RoundTrip(req)
+		})
+	}
+
+	return client, nil
+}
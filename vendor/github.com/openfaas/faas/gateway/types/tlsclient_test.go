@@ -0,0 +1,64 @@
+// Copyright (c) Alex Ellis 2017. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package types
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func Test_NewProviderHTTPClient_NoFile_UsesProviderTLSConfig(t *testing.T) {
+	cfg := GatewayConfig{ProviderTLSConfig: TLSConfig{InsecureSkipVerify: true}}
+
+	client, err := NewProviderHTTPClient(cfg, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	transport, ok := client.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("expected *http.Transport, got %T", client.Transport)
+	}
+	if !transport.TLSClientConfig.InsecureSkipVerify {
+		t.Fatal("expected InsecureSkipVerify to carry through from ProviderTLSConfig")
+	}
+}
+
+func Test_NewProviderHTTPClient_FileInjectsBearerToken(t *testing.T) {
+	f, err := ioutil.TempFile("", "provider-http-client-*.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.WriteString(`{"bearer_token": "s3cr3t"}`); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	f.Close()
+
+	cfg := GatewayConfig{ProviderHTTPClientFile: f.Name()}
+
+	client, err := NewProviderHTTPClient(cfg, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var gotAuth string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+	}))
+	defer upstream.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, upstream.URL, nil)
+	if _, err := client.Do(req); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if gotAuth != "Bearer s3cr3t" {
+		t.Fatalf("got Authorization header %q, want %q", gotAuth, "Bearer s3cr3t")
+	}
+}
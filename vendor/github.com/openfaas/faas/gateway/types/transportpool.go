@@ -0,0 +1,71 @@
+// Copyright (c) Alex Ellis 2017. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package types
+
+import (
+	"net/http"
+	"sync"
+)
+
+// transportKey identifies a distinct http.Transport configuration so
+// routes sharing the same tuning parameters can share one Transport
+// instead of leaking a new one per request.
+type transportKey struct {
+	maxIdleConnsPerHost int
+	upstreamTimeout     string
+}
+
+// TransportPool hands out an *http.Transport per distinct (MaxIdleConnsPerHost,
+// UpstreamTimeout) combination, reusing one for every route that resolves
+// to the same pair so the proxy dispatch layer doesn't have to build (and
+// eventually leak) a fresh Transport on every request. UpstreamTimeout is
+// applied as the Transport's ResponseHeaderTimeout, which only bounds the
+// wait for the upstream's response headers; it does not bound how long
+// reading the response body takes, so a function that starts responding
+// promptly but streams a slow body is not cut off by this alone. Bounding
+// body reads too would mean threading a per-request context deadline
+// through the dispatch call site, which doesn't exist in this package
+// today; callers that need a hard ceiling on total request time, such as
+// long-running batch functions, should set one on the request context
+// they pass to the transport's RoundTrip.
+type TransportPool struct {
+	maxIdleConns int
+
+	mu   sync.Mutex
+	pool map[transportKey]*http.Transport
+}
+
+// NewTransportPool returns a TransportPool whose transports all share
+// maxIdleConns as MaxIdleConns, mirroring GatewayConfig.MaxIdleConns.
+func NewTransportPool(maxIdleConns int) *TransportPool {
+	return &TransportPool{
+		maxIdleConns: maxIdleConns,
+		pool:         make(map[transportKey]*http.Transport),
+	}
+}
+
+// Get returns the Transport for rc, constructing and caching one on first
+// use for this combination of tuning parameters.
+func (p *TransportPool) Get(rc RouteConfig) *http.Transport {
+	key := transportKey{
+		maxIdleConnsPerHost: rc.MaxIdleConnsPerHost,
+		upstreamTimeout:     rc.UpstreamTimeout.String(),
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if t, ok := p.pool[key]; ok {
+		return t
+	}
+
+	t := &http.Transport{
+		MaxIdleConns:          p.maxIdleConns,
+		MaxIdleConnsPerHost:   rc.MaxIdleConnsPerHost,
+		ResponseHeaderTimeout: rc.UpstreamTimeout,
+	}
+	p.pool[key] = t
+
+	return t
+}
@@ -0,0 +1,42 @@
+// Copyright (c) Alex Ellis 2017. All rights reserved.
+// Licensed under the MIT license. See LICENSE file in the project root for full license information.
+
+package types
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_TransportPool_ReusesTransportForSameTuning(t *testing.T) {
+	pool := NewTransportPool(1024)
+	rc := RouteConfig{MaxIdleConnsPerHost: 256, UpstreamTimeout: 30 * time.Second}
+
+	first := pool.Get(rc)
+	second := pool.Get(rc)
+
+	if first != second {
+		t.Fatal("expected the same Transport instance for identical RouteConfig tuning")
+	}
+}
+
+func Test_TransportPool_SeparatesDifferentTuning(t *testing.T) {
+	pool := NewTransportPool(1024)
+
+	a := pool.Get(RouteConfig{MaxIdleConnsPerHost: 256, UpstreamTimeout: 30 * time.Second})
+	b := pool.Get(RouteConfig{MaxIdleConnsPerHost: 256, UpstreamTimeout: 5 * time.Minute})
+
+	if a == b {
+		t.Fatal("expected distinct Transports for distinct UpstreamTimeout values")
+	}
+}
+
+func Test_TransportPool_SetsResponseHeaderTimeoutFromUpstreamTimeout(t *testing.T) {
+	pool := NewTransportPool(1024)
+
+	transport := pool.Get(RouteConfig{UpstreamTimeout: 5 * time.Minute})
+
+	if transport.ResponseHeaderTimeout != 5*time.Minute {
+		t.Fatalf("got ResponseHeaderTimeout %s, want 5m", transport.ResponseHeaderTimeout)
+	}
+}